@@ -4,167 +4,275 @@ package aws
 //go:generate mockgen -source=client.go -package=mock -destination=mock/client.go
 
 import (
-	"path/filepath"
-
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/iam"
-	"github.com/aws/aws-sdk-go/service/iam/iamiface"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/s3/s3iface"
-	"github.com/aws/aws-sdk-go/service/sts"
-	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithymiddleware "github.com/aws/smithy-go/middleware"
 
 	"github.com/pkg/errors"
 )
 
+// osdctlVersion is reported to AWS (and any S3-compatible backend) via the outbound
+// user agent so operators can identify osdctl traffic in access logs.
+const osdctlVersion = "dev"
+
 // AwsClientInput input for new aws client
 type AwsClientInput struct {
 	AccessKeyID     string
 	SecretAccessKey string
 	SessionToken    string
 	Region          string
+
+	// S3Endpoint, when set, overrides the S3 endpoint only, so osdctl can target
+	// IBM Cloud Object Storage, MinIO, Ceph RGW, or LocalStack without affecting STS/IAM.
+	S3Endpoint string
+	// STSEndpoint, when set, overrides the STS endpoint only.
+	STSEndpoint string
+	// DisableSSL allows talking to http-only S3-compatible endpoints (e.g. local MinIO).
+	DisableSSL bool
+	// S3ForcePathStyle requests path-style S3 addressing, required by most
+	// S3-compatible backends that don't support virtual-hosted buckets.
+	S3ForcePathStyle bool
+}
+
+// withUserAgent appends "osdctl/<version>" to the user agent of every outbound
+// request so operators can identify osdctl traffic in S3/STS/IAM access logs.
+func withUserAgent() func(*smithymiddleware.Stack) error {
+	return awsmiddleware.AddUserAgentKeyValue("osdctl", osdctlVersion)
+}
+
+// stsAPIClient is the subset of *sts.Client used by this package, extracted so it can be mocked.
+type stsAPIClient interface {
+	AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+	GetFederationToken(ctx context.Context, params *sts.GetFederationTokenInput, optFns ...func(*sts.Options)) (*sts.GetFederationTokenOutput, error)
+}
+
+// s3APIClient is the subset of *s3.Client used by this package, extracted so it can be mocked.
+type s3APIClient interface {
+	ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	DeleteBucket(ctx context.Context, params *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
+	ListObjects(ctx context.Context, params *s3.ListObjectsInput, optFns ...func(*s3.Options)) (*s3.ListObjectsOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	ListObjectVersions(ctx context.Context, params *s3.ListObjectVersionsInput, optFns ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+}
+
+// iamAPIClient is the subset of *iam.Client used by this package, extracted so it can be mocked.
+type iamAPIClient interface {
+	CreateAccessKey(ctx context.Context, params *iam.CreateAccessKeyInput, optFns ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error)
+	DeleteAccessKey(ctx context.Context, params *iam.DeleteAccessKeyInput, optFns ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error)
+	ListAccessKeys(ctx context.Context, params *iam.ListAccessKeysInput, optFns ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error)
+	GetUser(ctx context.Context, params *iam.GetUserInput, optFns ...func(*iam.Options)) (*iam.GetUserOutput, error)
+	CreateUser(ctx context.Context, params *iam.CreateUserInput, optFns ...func(*iam.Options)) (*iam.CreateUserOutput, error)
+	ListUsers(ctx context.Context, params *iam.ListUsersInput, optFns ...func(*iam.Options)) (*iam.ListUsersOutput, error)
+	AttachUserPolicy(ctx context.Context, params *iam.AttachUserPolicyInput, optFns ...func(*iam.Options)) (*iam.AttachUserPolicyOutput, error)
 }
 
 // TODO: Add more methods when needed
 type Client interface {
 	// sts
-	AssumeRole(*sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error)
-	GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error)
-	GetFederationToken(*sts.GetFederationTokenInput) (*sts.GetFederationTokenOutput, error)
+	AssumeRole(ctx context.Context, in *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error)
+	GetCallerIdentity(ctx context.Context, in *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+	GetFederationToken(ctx context.Context, in *sts.GetFederationTokenInput, optFns ...func(*sts.Options)) (*sts.GetFederationTokenOutput, error)
 
 	// S3
-	ListBuckets(*s3.ListBucketsInput) (*s3.ListBucketsOutput, error)
-	DeleteBucket(*s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error)
-	ListObjects(*s3.ListObjectsInput) (*s3.ListObjectsOutput, error)
-	DeleteObjects(*s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error)
+	ListBuckets(ctx context.Context, in *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	DeleteBucket(ctx context.Context, in *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
+	ListObjects(ctx context.Context, in *s3.ListObjectsInput, optFns ...func(*s3.Options)) (*s3.ListObjectsOutput, error)
+	DeleteObjects(ctx context.Context, in *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+
+	// ListObjectsPagesWithContext walks every page of a ListObjectsV2 listing,
+	// invoking fn for each page until fn returns false or the last page is reached.
+	ListObjectsPagesWithContext(ctx context.Context, in *s3.ListObjectsV2Input, fn func(page *s3.ListObjectsV2Output, lastPage bool) bool) error
+
+	// BulkDeleteObjects deletes keys from bucket, chunking into ≤1000-key
+	// DeleteObjects batches and retrying on throttling errors. It returns a
+	// structured report of every object that was and wasn't deleted.
+	BulkDeleteObjects(ctx context.Context, bucket string, keys []string) ([]types.DeletedObject, []types.Error, error)
+
+	// PurgeObjectVersions deletes every object version and delete marker in
+	// bucket via a single paginated ListObjectVersions scan, so callers emptying
+	// a versioned bucket don't leave old versions behind. It returns a
+	// structured report of every version that was and wasn't deleted.
+	PurgeObjectVersions(ctx context.Context, bucket string) ([]types.DeletedObject, []types.Error, error)
 
 	//iam
-	CreateAccessKey(*iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error)
-	DeleteAccessKey(*iam.DeleteAccessKeyInput) (*iam.DeleteAccessKeyOutput, error)
-	ListAccessKeys(*iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error)
-	GetUser(*iam.GetUserInput) (*iam.GetUserOutput, error)
-	CreateUser(*iam.CreateUserInput) (*iam.CreateUserOutput, error)
-	ListUsers(*iam.ListUsersInput) (*iam.ListUsersOutput, error)
-	AttachUserPolicy(*iam.AttachUserPolicyInput) (*iam.AttachUserPolicyOutput, error)
+	CreateAccessKey(ctx context.Context, in *iam.CreateAccessKeyInput, optFns ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error)
+	DeleteAccessKey(ctx context.Context, in *iam.DeleteAccessKeyInput, optFns ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error)
+	ListAccessKeys(ctx context.Context, in *iam.ListAccessKeysInput, optFns ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error)
+	GetUser(ctx context.Context, in *iam.GetUserInput, optFns ...func(*iam.Options)) (*iam.GetUserOutput, error)
+	CreateUser(ctx context.Context, in *iam.CreateUserInput, optFns ...func(*iam.Options)) (*iam.CreateUserOutput, error)
+	ListUsers(ctx context.Context, in *iam.ListUsersInput, optFns ...func(*iam.Options)) (*iam.ListUsersOutput, error)
+	AttachUserPolicy(ctx context.Context, in *iam.AttachUserPolicyInput, optFns ...func(*iam.Options)) (*iam.AttachUserPolicyOutput, error)
 }
 
 type AwsClient struct {
-	iamClient iamiface.IAMAPI
-	stsClient stsiface.STSAPI
-	s3Client  s3iface.S3API
+	cfg       aws.Config
+	iamClient iamAPIClient
+	stsClient stsAPIClient
+	s3Client  s3APIClient
+
+	// endpoints carries the S3/STS endpoint overrides (if any) this client was
+	// built with, so AssumeRoleClient can re-apply them to the service clients
+	// it rebuilds on top of the assumed-role credentials instead of silently
+	// falling back to the real AWS endpoints.
+	endpoints endpointOverrides
+}
+
+// endpointOverrides holds the S3-compatible-backend options from
+// AwsClientInput that live only in per-service Options, not in aws.Config, so
+// they don't survive a bare cfg.Copy().
+type endpointOverrides struct {
+	s3Endpoint       string
+	stsEndpoint      string
+	disableSSL       bool
+	s3ForcePathStyle bool
 }
 
 // NewAwsClient creates an AWS client with credentials in the environment
-func NewAwsClient(profile, region, configFile string) (Client, error) {
-	opt := session.Options{
-		Config: aws.Config{
-			Region: aws.String(region),
-		},
-		Profile: profile,
+func NewAwsClient(ctx context.Context, profile, region, configFile string) (Client, error) {
+	loadOpts := []func(*config.LoadOptions) error{
+		config.WithRegion(region),
+		config.WithSharedConfigProfile(profile),
+		config.WithAPIOptions([]func(*smithymiddleware.Stack) error{withUserAgent()}),
 	}
 
 	// only set config file if it is not empty
 	if configFile != "" {
-		absCfgPath, err := filepath.Abs(configFile)
-		if err != nil {
-			return nil, err
-		}
-		opt.SharedConfigFiles = []string{absCfgPath}
+		loadOpts = append(loadOpts, config.WithSharedConfigFiles([]string{configFile}))
 	}
 
-	sess := session.Must(session.NewSessionWithOptions(opt))
-	_, err := sess.Config.Credentials.Get()
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not create AWS session")
+	}
 
-	if aerr, ok := err.(awserr.Error); ok {
-		switch aerr.Code() {
-		case "NoCredentialProviders":
-			return nil, errors.Wrap(err, "Could not create AWS session")
-		default:
-			return nil, errors.Wrap(err, "Could not create AWS session")
-		}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return nil, errors.Wrap(err, "Could not create AWS session")
 	}
 
 	return &AwsClient{
-		iamClient: iam.New(sess),
-		stsClient: sts.New(sess),
-		s3Client:  s3.New(sess),
+		cfg:       cfg,
+		iamClient: iam.NewFromConfig(cfg),
+		stsClient: sts.NewFromConfig(cfg),
+		s3Client:  s3.NewFromConfig(cfg),
 	}, nil
 }
 
-// NewAwsClientWithInput creates an AWS client with input credentials
-func NewAwsClientWithInput(input *AwsClientInput) (Client, error) {
-	config := &aws.Config{
-		Credentials: credentials.NewStaticCredentials(input.AccessKeyID, input.SecretAccessKey, input.SessionToken),
-		Region:      aws.String(input.Region),
-	}
-
-	s, err := session.NewSession(config)
+// NewAwsClientWithInput creates an AWS client with input credentials. Setting
+// input.S3Endpoint (and optionally input.S3ForcePathStyle/input.DisableSSL) points
+// only the S3 client at an S3-compatible backend such as IBM COS, MinIO, Ceph RGW,
+// or LocalStack; STS/IAM continue to hit the real AWS endpoints unless
+// input.STSEndpoint is also set.
+func NewAwsClientWithInput(ctx context.Context, input *AwsClientInput) (Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(input.Region),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(input.AccessKeyID, input.SecretAccessKey, input.SessionToken)),
+		config.WithAPIOptions([]func(*smithymiddleware.Stack) error{withUserAgent()}),
+	)
 	if err != nil {
 		return nil, err
 	}
 
+	endpoints := endpointOverrides{
+		s3Endpoint:       input.S3Endpoint,
+		stsEndpoint:      input.STSEndpoint,
+		disableSSL:       input.DisableSSL,
+		s3ForcePathStyle: input.S3ForcePathStyle,
+	}
+
 	return &AwsClient{
-		iamClient: iam.New(s),
-		stsClient: sts.New(s),
-		s3Client:  s3.New(s),
+		cfg:       cfg,
+		endpoints: endpoints,
+		iamClient: iam.NewFromConfig(cfg),
+		stsClient: sts.NewFromConfig(cfg, endpoints.stsOptions()),
+		s3Client:  s3.NewFromConfig(cfg, endpoints.s3Options()),
 	}, nil
 }
 
-func (c *AwsClient) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
-	return c.stsClient.AssumeRole(input)
+// stsOptions applies this client's STS endpoint override (if any) to an
+// sts.Options, for both the initial client and any AssumeRoleClient rebuild.
+func (e endpointOverrides) stsOptions() func(*sts.Options) {
+	return func(o *sts.Options) {
+		if e.stsEndpoint != "" {
+			o.BaseEndpoint = aws.String(e.stsEndpoint)
+		}
+	}
+}
+
+// s3Options applies this client's S3 endpoint/path-style/SSL overrides (if
+// any) to an s3.Options, for both the initial client and any AssumeRoleClient
+// rebuild.
+func (e endpointOverrides) s3Options() func(*s3.Options) {
+	return func(o *s3.Options) {
+		if e.s3Endpoint != "" {
+			o.BaseEndpoint = aws.String(e.s3Endpoint)
+		}
+		o.UsePathStyle = e.s3ForcePathStyle
+		o.EndpointOptions.DisableHTTPS = e.disableSSL
+	}
+}
+
+func (c *AwsClient) AssumeRole(ctx context.Context, in *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	return c.stsClient.AssumeRole(ctx, in, optFns...)
 }
 
-func (c *AwsClient) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
-	return c.stsClient.GetCallerIdentity(input)
+func (c *AwsClient) GetCallerIdentity(ctx context.Context, in *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return c.stsClient.GetCallerIdentity(ctx, in, optFns...)
 }
 
-func (c *AwsClient) GetFederationToken(input *sts.GetFederationTokenInput) (*sts.GetFederationTokenOutput, error) {
-	return c.stsClient.GetFederationToken(input)
+func (c *AwsClient) GetFederationToken(ctx context.Context, in *sts.GetFederationTokenInput, optFns ...func(*sts.Options)) (*sts.GetFederationTokenOutput, error) {
+	return c.stsClient.GetFederationToken(ctx, in, optFns...)
 }
 
-func (c *AwsClient) ListBuckets(input *s3.ListBucketsInput) (*s3.ListBucketsOutput, error) {
-	return c.s3Client.ListBuckets(input)
+func (c *AwsClient) ListBuckets(ctx context.Context, in *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	return c.s3Client.ListBuckets(ctx, in, optFns...)
 }
 
-func (c *AwsClient) DeleteBucket(input *s3.DeleteBucketInput) (*s3.DeleteBucketOutput, error) {
-	return c.s3Client.DeleteBucket(input)
+func (c *AwsClient) DeleteBucket(ctx context.Context, in *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+	return c.s3Client.DeleteBucket(ctx, in, optFns...)
 }
 
-func (c *AwsClient) ListObjects(input *s3.ListObjectsInput) (*s3.ListObjectsOutput, error) {
-	return c.s3Client.ListObjects(input)
+func (c *AwsClient) ListObjects(ctx context.Context, in *s3.ListObjectsInput, optFns ...func(*s3.Options)) (*s3.ListObjectsOutput, error) {
+	return c.s3Client.ListObjects(ctx, in, optFns...)
 }
 
-func (c *AwsClient) DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
-	return c.s3Client.DeleteObjects(input)
+func (c *AwsClient) DeleteObjects(ctx context.Context, in *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	return c.s3Client.DeleteObjects(ctx, in, optFns...)
 }
 
-func (c *AwsClient) CreateAccessKey(input *iam.CreateAccessKeyInput) (*iam.CreateAccessKeyOutput, error) {
-	return c.iamClient.CreateAccessKey(input)
+func (c *AwsClient) CreateAccessKey(ctx context.Context, in *iam.CreateAccessKeyInput, optFns ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error) {
+	return c.iamClient.CreateAccessKey(ctx, in, optFns...)
 }
 
-func (c *AwsClient) DeleteAccessKey(input *iam.DeleteAccessKeyInput) (*iam.DeleteAccessKeyOutput, error) {
-	return c.iamClient.DeleteAccessKey(input)
+func (c *AwsClient) DeleteAccessKey(ctx context.Context, in *iam.DeleteAccessKeyInput, optFns ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error) {
+	return c.iamClient.DeleteAccessKey(ctx, in, optFns...)
 }
 
-func (c *AwsClient) ListAccessKeys(input *iam.ListAccessKeysInput) (*iam.ListAccessKeysOutput, error) {
-	return c.iamClient.ListAccessKeys(input)
+func (c *AwsClient) ListAccessKeys(ctx context.Context, in *iam.ListAccessKeysInput, optFns ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+	return c.iamClient.ListAccessKeys(ctx, in, optFns...)
 }
 
-func (c *AwsClient) GetUser(input *iam.GetUserInput) (*iam.GetUserOutput, error) {
-	return c.iamClient.GetUser(input)
+func (c *AwsClient) GetUser(ctx context.Context, in *iam.GetUserInput, optFns ...func(*iam.Options)) (*iam.GetUserOutput, error) {
+	return c.iamClient.GetUser(ctx, in, optFns...)
 }
 
-func (c *AwsClient) CreateUser(input *iam.CreateUserInput) (*iam.CreateUserOutput, error) {
-	return c.iamClient.CreateUser(input)
+func (c *AwsClient) CreateUser(ctx context.Context, in *iam.CreateUserInput, optFns ...func(*iam.Options)) (*iam.CreateUserOutput, error) {
+	return c.iamClient.CreateUser(ctx, in, optFns...)
 }
 
-func (c *AwsClient) ListUsers(input *iam.ListUsersInput) (*iam.ListUsersOutput, error) {
-	return c.iamClient.ListUsers(input)
+func (c *AwsClient) ListUsers(ctx context.Context, in *iam.ListUsersInput, optFns ...func(*iam.Options)) (*iam.ListUsersOutput, error) {
+	return c.iamClient.ListUsers(ctx, in, optFns...)
 }
 
-func (c *AwsClient) AttachUserPolicy(input *iam.AttachUserPolicyInput) (*iam.AttachUserPolicyOutput, error) {
-	return c.iamClient.AttachUserPolicy(input)
+func (c *AwsClient) AttachUserPolicy(ctx context.Context, in *iam.AttachUserPolicyInput, optFns ...func(*iam.Options)) (*iam.AttachUserPolicyOutput, error) {
+	return c.iamClient.AttachUserPolicy(ctx, in, optFns...)
 }
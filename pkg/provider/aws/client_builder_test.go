@@ -0,0 +1,90 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+func TestClientBuilderCacheKeyIncludesSessionNameAndDuration(t *testing.T) {
+	b := NewClientBuilder()
+
+	base := &AwsClient{}
+	first := cacheKey{profile: "p", region: "us-east-1", roleARN: "arn:aws:iam::111111111111:role/foo", externalID: "ext", sessionName: "session-a", duration: time.Hour}
+	second := cacheKey{profile: "p", region: "us-east-1", roleARN: "arn:aws:iam::111111111111:role/foo", externalID: "ext", sessionName: "session-b", duration: time.Hour}
+	third := cacheKey{profile: "p", region: "us-east-1", roleARN: "arn:aws:iam::111111111111:role/foo", externalID: "ext", sessionName: "session-a", duration: 2 * time.Hour}
+
+	if first == second {
+		t.Fatalf("cache keys with different session names must not collide")
+	}
+	if first == third {
+		t.Fatalf("cache keys with different durations must not collide")
+	}
+
+	b.store(first, base)
+	if _, ok := b.load(second); ok {
+		t.Fatalf("a client cached under one session name must not be returned for another")
+	}
+	if _, ok := b.load(third); ok {
+		t.Fatalf("a client cached under one duration must not be returned for another")
+	}
+	if c, ok := b.load(first); !ok || c != base {
+		t.Fatalf("expected to load back the client stored under its own key")
+	}
+}
+
+func TestAssumeRoleClientCarriesEndpointOverrides(t *testing.T) {
+	base := &AwsClient{
+		cfg: aws.Config{Region: "us-east-1"},
+		endpoints: endpointOverrides{
+			s3Endpoint:       "https://s3.example.test",
+			stsEndpoint:      "https://sts.example.test",
+			s3ForcePathStyle: true,
+			disableSSL:       true,
+		},
+		stsClient: &fakeStsClient{},
+	}
+
+	assumed, err := AssumeRoleClient(base, "arn:aws:iam::111111111111:role/foo", "session", "", time.Hour)
+	if err != nil {
+		t.Fatalf("AssumeRoleClient failed: %v", err)
+	}
+
+	ac, ok := assumed.(*AwsClient)
+	if !ok {
+		t.Fatalf("expected AssumeRoleClient to return an *AwsClient, got %T", assumed)
+	}
+
+	s3c, ok := ac.s3Client.(*s3.Client)
+	if !ok {
+		t.Fatalf("expected s3Client to be an *s3.Client, got %T", ac.s3Client)
+	}
+	s3Opts := s3c.Options()
+	if got := aws.ToString(s3Opts.BaseEndpoint); got != base.endpoints.s3Endpoint {
+		t.Fatalf("expected assumed-role S3 client to keep BaseEndpoint %q, got %q", base.endpoints.s3Endpoint, got)
+	}
+	if !s3Opts.UsePathStyle {
+		t.Fatalf("expected assumed-role S3 client to keep UsePathStyle")
+	}
+	if !s3Opts.EndpointOptions.DisableHTTPS {
+		t.Fatalf("expected assumed-role S3 client to keep DisableHTTPS")
+	}
+
+	stsc, ok := ac.stsClient.(*sts.Client)
+	if !ok {
+		t.Fatalf("expected stsClient to be an *sts.Client, got %T", ac.stsClient)
+	}
+	if got := aws.ToString(stsc.Options().BaseEndpoint); got != base.endpoints.stsEndpoint {
+		t.Fatalf("expected assumed-role STS client to keep BaseEndpoint %q, got %q", base.endpoints.stsEndpoint, got)
+	}
+}
+
+// fakeStsClient satisfies stscreds.AssumeRoleAPIClient so AssumeRoleClient can
+// build a credentials provider around base without making a real STS call;
+// AssumeRoleClient itself never invokes AssumeRole.
+type fakeStsClient struct {
+	stsAPIClient
+}
@@ -0,0 +1,208 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// deleteBatchSize is the maximum number of keys S3's DeleteObjects API accepts
+// in a single request.
+const deleteBatchSize = 1000
+
+// retryableS3ErrorCodes are the S3 throttling errors worth retrying with backoff
+// rather than failing the whole bulk delete.
+var retryableS3ErrorCodes = map[string]bool{
+	"RequestLimitExceeded": true,
+	"SlowDown":             true,
+}
+
+// ListObjectsPagesWithContext walks every page of a ListObjectsV2 listing,
+// invoking fn for each page until fn returns false or the last page is reached.
+func (c *AwsClient) ListObjectsPagesWithContext(ctx context.Context, in *s3.ListObjectsV2Input, fn func(page *s3.ListObjectsV2Output, lastPage bool) bool) error {
+	input := *in
+	for {
+		page, err := c.s3Client.ListObjectsV2(ctx, &input)
+		if err != nil {
+			return err
+		}
+
+		lastPage := !aws.ToBool(page.IsTruncated)
+		if !fn(page, lastPage) || lastPage {
+			return nil
+		}
+
+		input.ContinuationToken = page.NextContinuationToken
+	}
+}
+
+// BulkDeleteObjects deletes keys from bucket, chunking into ≤1000-key
+// DeleteObjects batches and retrying on RequestLimitExceeded/SlowDown with
+// exponential backoff. It returns every object that was and wasn't deleted;
+// per-object failures in one batch do not stop the later batches. A
+// DeleteObjects call that fails outright with a non-throttling error stops the
+// bulk delete: that error is returned together with the report of the batches
+// completed before it, and the remaining batches are not attempted. Callers emptying a versioned
+// bucket should also call PurgeObjectVersions once the current objects are
+// gone, to remove the versions/delete markers left behind.
+func (c *AwsClient) BulkDeleteObjects(ctx context.Context, bucket string, keys []string) ([]types.DeletedObject, []types.Error, error) {
+	objects := make([]types.ObjectIdentifier, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+	}
+
+	var deleted []types.DeletedObject
+	var failed []types.Error
+	err := c.batchDelete(ctx, bucket, objects, &deleted, &failed)
+	return deleted, failed, err
+}
+
+// PurgeObjectVersions deletes every object version and delete marker in bucket.
+// It walks ListObjectVersions exactly once, paginated, flushing a DeleteObjects
+// batch every time it accumulates deleteBatchSize identifiers, so emptying an
+// N-object versioned bucket costs O(N) S3 calls rather than re-scanning the
+// whole bucket for every batch of objects deleted elsewhere.
+func (c *AwsClient) PurgeObjectVersions(ctx context.Context, bucket string) ([]types.DeletedObject, []types.Error, error) {
+	var deleted []types.DeletedObject
+	var failed []types.Error
+	var pending []types.ObjectIdentifier
+
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		err := c.batchDelete(ctx, bucket, pending, &deleted, &failed)
+		pending = pending[:0]
+		return err
+	}
+
+	in := &s3.ListObjectVersionsInput{Bucket: aws.String(bucket)}
+	for {
+		out, err := c.s3Client.ListObjectVersions(ctx, in)
+		if err != nil {
+			return deleted, failed, err
+		}
+
+		for _, v := range out.Versions {
+			pending = append(pending, types.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+			if len(pending) == deleteBatchSize {
+				if err := flush(); err != nil {
+					return deleted, failed, err
+				}
+			}
+		}
+		for _, m := range out.DeleteMarkers {
+			pending = append(pending, types.ObjectIdentifier{Key: m.Key, VersionId: m.VersionId})
+			if len(pending) == deleteBatchSize {
+				if err := flush(); err != nil {
+					return deleted, failed, err
+				}
+			}
+		}
+
+		if !aws.ToBool(out.IsTruncated) {
+			return deleted, failed, flush()
+		}
+		in.KeyMarker = out.NextKeyMarker
+		in.VersionIdMarker = out.NextVersionIdMarker
+	}
+}
+
+// batchDelete issues DeleteObjects in groups of deleteBatchSize, appending
+// results onto deleted/failed.
+func (c *AwsClient) batchDelete(ctx context.Context, bucket string, objects []types.ObjectIdentifier, deleted *[]types.DeletedObject, failed *[]types.Error) error {
+	for start := 0; start < len(objects); start += deleteBatchSize {
+		end := start + deleteBatchSize
+		if end > len(objects) {
+			end = len(objects)
+		}
+
+		batchDeleted, batchFailed, err := c.deleteObjectsWithRetry(ctx, bucket, objects[start:end])
+		if err != nil {
+			return err
+		}
+
+		*deleted = append(*deleted, batchDeleted...)
+		*failed = append(*failed, batchFailed...)
+	}
+	return nil
+}
+
+// deleteObjectsWithRetry issues DeleteObjects for objects, retrying with
+// exponential backoff both on a call-level throttling error and on the
+// RequestLimitExceeded/SlowDown entries S3 can return inline in a successful
+// response's Errors list. Only the objects still carrying a retryable error
+// are re-driven on each attempt; every other object is resolved after the
+// first call that reports it.
+func (c *AwsClient) deleteObjectsWithRetry(ctx context.Context, bucket string, objects []types.ObjectIdentifier) ([]types.DeletedObject, []types.Error, error) {
+	const maxAttempts = 5
+	backoff := 200 * time.Millisecond
+
+	var deleted []types.DeletedObject
+	var failed []types.Error
+	pending := objects
+
+	for attempt := 0; attempt < maxAttempts && len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return deleted, failed, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		out, err := c.s3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{Objects: pending},
+		})
+		if err != nil {
+			if !isRetryableS3Error(err) {
+				return deleted, failed, err
+			}
+			continue
+		}
+
+		deleted = append(deleted, out.Deleted...)
+
+		// Reslice to a zero-capacity empty slice rather than pending[:0]: pending
+		// shares a backing array with the objects slice batchDelete passed in, and
+		// appending onto pending[:0] would silently overwrite it.
+		pending = pending[:0:0]
+		for _, objErr := range out.Errors {
+			if isRetryableS3ErrorCode(aws.ToString(objErr.Code)) {
+				pending = append(pending, types.ObjectIdentifier{Key: objErr.Key, VersionId: objErr.VersionId})
+				continue
+			}
+			failed = append(failed, objErr)
+		}
+	}
+
+	for _, obj := range pending {
+		failed = append(failed, types.Error{
+			Key:       obj.Key,
+			VersionId: obj.VersionId,
+			Code:      aws.String("SlowDown"),
+			Message:   aws.String("exceeded retry attempts for throttled delete"),
+		})
+	}
+
+	return deleted, failed, nil
+}
+
+func isRetryableS3Error(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return isRetryableS3ErrorCode(apiErr.ErrorCode())
+	}
+	return false
+}
+
+func isRetryableS3ErrorCode(code string) bool {
+	return retryableS3ErrorCodes[code]
+}
@@ -0,0 +1,193 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithy "github.com/aws/smithy-go"
+)
+
+// fakeS3Client is a minimal s3APIClient stand-in that lets each test script the
+// responses ListObjectsV2/ListObjectVersions/DeleteObjects return, without
+// hitting a real S3 endpoint.
+type fakeS3Client struct {
+	s3APIClient
+
+	listObjectsV2      func(in *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error)
+	listObjectVersions func(in *s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error)
+	deleteObjects      func(in *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error)
+
+	deleteObjectsCalls []*s3.DeleteObjectsInput
+}
+
+func (f *fakeS3Client) ListObjectsV2(ctx context.Context, in *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	return f.listObjectsV2(in)
+}
+
+func (f *fakeS3Client) ListObjectVersions(ctx context.Context, in *s3.ListObjectVersionsInput, optFns ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+	return f.listObjectVersions(in)
+}
+
+func (f *fakeS3Client) DeleteObjects(ctx context.Context, in *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	f.deleteObjectsCalls = append(f.deleteObjectsCalls, in)
+	return f.deleteObjects(in)
+}
+
+func throttleErr(code string) error {
+	return &smithy.GenericAPIError{Code: code, Message: "throttled"}
+}
+
+func TestBulkDeleteObjectsRetriesCallLevelThrottleError(t *testing.T) {
+	attempts := 0
+	fake := &fakeS3Client{
+		deleteObjects: func(in *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, throttleErr("SlowDown")
+			}
+			deleted := make([]types.DeletedObject, len(in.Delete.Objects))
+			for i, obj := range in.Delete.Objects {
+				deleted[i] = types.DeletedObject{Key: obj.Key}
+			}
+			return &s3.DeleteObjectsOutput{Deleted: deleted}, nil
+		},
+	}
+	c := &AwsClient{s3Client: fake}
+
+	deleted, failed, err := c.BulkDeleteObjects(context.Background(), "bucket", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("expected no permanent failures, got %+v", failed)
+	}
+	if len(deleted) != 2 {
+		t.Fatalf("expected both keys deleted after retry, got %d", len(deleted))
+	}
+	if attempts != 2 {
+		t.Fatalf("expected exactly 2 DeleteObjects calls, got %d", attempts)
+	}
+}
+
+func TestBulkDeleteObjectsRetriesInlineSlowDownEntry(t *testing.T) {
+	calls := 0
+	fake := &fakeS3Client{
+		deleteObjects: func(in *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			calls++
+			if calls == 1 {
+				var deleted []types.DeletedObject
+				var errs []types.Error
+				for _, obj := range in.Delete.Objects {
+					if aws.ToString(obj.Key) == "b" {
+						errs = append(errs, types.Error{Key: obj.Key, Code: aws.String("SlowDown")})
+						continue
+					}
+					deleted = append(deleted, types.DeletedObject{Key: obj.Key})
+				}
+				return &s3.DeleteObjectsOutput{Deleted: deleted, Errors: errs}, nil
+			}
+
+			deleted := make([]types.DeletedObject, len(in.Delete.Objects))
+			for i, obj := range in.Delete.Objects {
+				deleted[i] = types.DeletedObject{Key: obj.Key}
+			}
+			return &s3.DeleteObjectsOutput{Deleted: deleted}, nil
+		},
+	}
+	c := &AwsClient{s3Client: fake}
+
+	deleted, failed, err := c.BulkDeleteObjects(context.Background(), "bucket", []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("expected the inline SlowDown entry to be retried away, got failures: %+v", failed)
+	}
+	if len(deleted) != 3 {
+		t.Fatalf("expected all 3 keys deleted, got %d", len(deleted))
+	}
+	if calls != 2 {
+		t.Fatalf("expected exactly 2 DeleteObjects calls (initial + retry of the SlowDown key), got %d", calls)
+	}
+	if len(fake.deleteObjectsCalls[1].Delete.Objects) != 1 || aws.ToString(fake.deleteObjectsCalls[1].Delete.Objects[0].Key) != "b" {
+		t.Fatalf("expected the retry call to carry only key b, got %+v", fake.deleteObjectsCalls[1].Delete.Objects)
+	}
+}
+
+func TestListObjectsPagesWithContextWalksMultiplePages(t *testing.T) {
+	var seenTokens []*string
+	fake := &fakeS3Client{
+		listObjectsV2: func(in *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+			seenTokens = append(seenTokens, in.ContinuationToken)
+			if in.ContinuationToken == nil {
+				return &s3.ListObjectsV2Output{
+					IsTruncated:           aws.Bool(true),
+					NextContinuationToken: aws.String("page-2"),
+				}, nil
+			}
+			return &s3.ListObjectsV2Output{IsTruncated: aws.Bool(false)}, nil
+		},
+	}
+	c := &AwsClient{s3Client: fake}
+
+	var pages int
+	err := c.ListObjectsPagesWithContext(context.Background(), &s3.ListObjectsV2Input{}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		pages++
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pages != 2 {
+		t.Fatalf("expected 2 pages, got %d", pages)
+	}
+	if len(seenTokens) != 2 || seenTokens[0] != nil || aws.ToString(seenTokens[1]) != "page-2" {
+		t.Fatalf("expected the second call to carry the first page's continuation token, got %+v", seenTokens)
+	}
+}
+
+func TestPurgeObjectVersionsFlushesAtBatchBoundary(t *testing.T) {
+	const total = deleteBatchSize + 5
+
+	versions := make([]types.ObjectVersion, total)
+	for i := range versions {
+		versions[i] = types.ObjectVersion{Key: aws.String("key"), VersionId: aws.String("v")}
+	}
+
+	fake := &fakeS3Client{
+		listObjectVersions: func(in *s3.ListObjectVersionsInput) (*s3.ListObjectVersionsOutput, error) {
+			return &s3.ListObjectVersionsOutput{Versions: versions, IsTruncated: aws.Bool(false)}, nil
+		},
+		deleteObjects: func(in *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+			deleted := make([]types.DeletedObject, len(in.Delete.Objects))
+			for i, obj := range in.Delete.Objects {
+				deleted[i] = types.DeletedObject{Key: obj.Key}
+			}
+			return &s3.DeleteObjectsOutput{Deleted: deleted}, nil
+		},
+	}
+	c := &AwsClient{s3Client: fake}
+
+	deleted, failed, err := c.PurgeObjectVersions(context.Background(), "bucket")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(failed) != 0 {
+		t.Fatalf("expected no failures, got %+v", failed)
+	}
+	if len(deleted) != total {
+		t.Fatalf("expected all %d versions deleted, got %d", total, len(deleted))
+	}
+	if len(fake.deleteObjectsCalls) != 2 {
+		t.Fatalf("expected a flush at the %d boundary plus one for the remainder (2 calls total), got %d", deleteBatchSize, len(fake.deleteObjectsCalls))
+	}
+	if len(fake.deleteObjectsCalls[0].Delete.Objects) != deleteBatchSize {
+		t.Fatalf("expected the first flush to carry exactly %d objects, got %d", deleteBatchSize, len(fake.deleteObjectsCalls[0].Delete.Objects))
+	}
+	if len(fake.deleteObjectsCalls[1].Delete.Objects) != 5 {
+		t.Fatalf("expected the second flush to carry the remaining 5 objects, got %d", len(fake.deleteObjectsCalls[1].Delete.Objects))
+	}
+}
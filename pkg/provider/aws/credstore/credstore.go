@@ -0,0 +1,138 @@
+// Package credstore persists short-lived AWS credentials produced by
+// RequestSignInToken/GetAssumeRoleCredentials so later osdctl invocations (or
+// other tools) can reuse them without re-assuming the role.
+package credstore
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/pkg/errors"
+	"gopkg.in/ini.v1"
+
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+)
+
+// Backend selects where Store persists credentials.
+type Backend string
+
+const (
+	// BackendFile writes profiles into ~/.aws/credentials, the default so
+	// osdctl hands credentials off the same way the AWS CLI and SDKs expect.
+	BackendFile Backend = "file"
+	// BackendKeyring stores credentials in the OS keyring instead of on disk.
+	BackendKeyring Backend = "keyring"
+)
+
+// profileNamePattern matches a safe ini section name: callers choose their own
+// profile name (it need not be an AWS account ID), but it must not contain
+// "[", "]", or a newline, which would let it inject a second section or
+// corrupt the rest of ~/.aws/credentials.
+var profileNamePattern = regexp.MustCompile(`^[^\[\]\r\n]+$`)
+
+// Store saves and loads AWS credentials under a named profile.
+type Store struct {
+	backend         Backend
+	credentialsFile string
+}
+
+// NewStore returns a Store using backend. For BackendFile, credentials are
+// written to ~/.aws/credentials.
+func NewStore(backend Backend) (*Store, error) {
+	s := &Store{backend: backend}
+
+	if backend == BackendFile {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not determine home directory")
+		}
+		s.credentialsFile = filepath.Join(home, ".aws", "credentials")
+	}
+
+	return s, nil
+}
+
+// SaveAssumedRole persists creds under profile using the configured backend.
+func (s *Store) SaveAssumedRole(profile string, creds *ststypes.Credentials) error {
+	if err := validateProfileName(profile); err != nil {
+		return err
+	}
+
+	switch s.backend {
+	case BackendKeyring:
+		return s.saveToKeyring(profile, creds)
+	default:
+		return s.saveToFile(profile, creds)
+	}
+}
+
+// LoadProfile loads a previously saved profile back into an AwsClientInput so
+// it can seed NewAwsClientWithInput without re-assuming the role.
+func (s *Store) LoadProfile(profile string) (*awsprovider.AwsClientInput, error) {
+	switch s.backend {
+	case BackendKeyring:
+		return s.loadFromKeyring(profile)
+	default:
+		return s.loadFromFile(profile)
+	}
+}
+
+func (s *Store) saveToFile(profile string, creds *ststypes.Credentials) error {
+	cfg, err := ini.LoadSources(ini.LoadOptions{IgnoreInlineComment: true, Loose: true}, s.credentialsFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load %s", s.credentialsFile)
+	}
+
+	sec, err := cfg.NewSection(profile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create profile section %s", profile)
+	}
+
+	sec.Key("aws_access_key_id").SetValue(awsString(creds.AccessKeyId))
+	sec.Key("aws_secret_access_key").SetValue(awsString(creds.SecretAccessKey))
+	sec.Key("aws_session_token").SetValue(awsString(creds.SessionToken))
+	if creds.Expiration != nil {
+		sec.Key("expiration").SetValue(creds.Expiration.Format(time.RFC3339))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.credentialsFile), 0o700); err != nil {
+		return errors.Wrapf(err, "failed to create %s", filepath.Dir(s.credentialsFile))
+	}
+
+	return cfg.SaveTo(s.credentialsFile)
+}
+
+func (s *Store) loadFromFile(profile string) (*awsprovider.AwsClientInput, error) {
+	cfg, err := ini.Load(s.credentialsFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load %s", s.credentialsFile)
+	}
+
+	sec, err := cfg.GetSection(profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "no profile %s in %s", profile, s.credentialsFile)
+	}
+
+	return &awsprovider.AwsClientInput{
+		AccessKeyID:     sec.Key("aws_access_key_id").String(),
+		SecretAccessKey: sec.Key("aws_secret_access_key").String(),
+		SessionToken:    sec.Key("aws_session_token").String(),
+	}, nil
+}
+
+func validateProfileName(profile string) error {
+	if !profileNamePattern.MatchString(profile) {
+		return errors.Errorf("profile name %q must not be empty or contain '[', ']', or a newline", profile)
+	}
+	return nil
+}
+
+func awsString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,125 @@
+package credstore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"gopkg.in/ini.v1"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	cases := []struct {
+		name    string
+		profile string
+		wantErr bool
+	}{
+		{name: "plain name", profile: "prod-readonly", wantErr: false},
+		{name: "account id", profile: "123456789012", wantErr: false},
+		{name: "empty", profile: "", wantErr: true},
+		{name: "open bracket", profile: "prod[readonly", wantErr: true},
+		{name: "close bracket", profile: "prod]readonly", wantErr: true},
+		{name: "newline", profile: "prod\nreadonly", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateProfileName(tc.profile)
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected an error for profile %q, got none", tc.profile)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error for profile %q: %v", tc.profile, err)
+			}
+		})
+	}
+}
+
+func TestSaveToFileThenLoadFromFileRoundTrips(t *testing.T) {
+	dir := t.TempDir()
+	credentialsFile := filepath.Join(dir, "credentials")
+	s := &Store{backend: BackendFile, credentialsFile: credentialsFile}
+
+	creds := &ststypes.Credentials{
+		AccessKeyId:     aws.String("AKIAEXAMPLE"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+	}
+
+	if err := s.SaveAssumedRole("prod-readonly", creds); err != nil {
+		t.Fatalf("SaveAssumedRole failed: %v", err)
+	}
+
+	loaded, err := s.LoadProfile("prod-readonly")
+	if err != nil {
+		t.Fatalf("LoadProfile failed: %v", err)
+	}
+	if loaded.AccessKeyID != "AKIAEXAMPLE" || loaded.SecretAccessKey != "secret" || loaded.SessionToken != "token" {
+		t.Fatalf("loaded credentials don't match what was saved: %+v", loaded)
+	}
+}
+
+func TestSaveToFilePreservesOtherSectionsAndOverwritesExistingProfile(t *testing.T) {
+	dir := t.TempDir()
+	credentialsFile := filepath.Join(dir, "credentials")
+
+	existing := "# a hand-written comment\n[default]\naws_access_key_id = DEFAULTKEY\n\n[prod-readonly]\naws_access_key_id = STALEKEY\naws_secret_access_key = stale-secret\n"
+	if err := os.WriteFile(credentialsFile, []byte(existing), 0o600); err != nil {
+		t.Fatalf("failed to seed credentials file: %v", err)
+	}
+
+	s := &Store{backend: BackendFile, credentialsFile: credentialsFile}
+	creds := &ststypes.Credentials{
+		AccessKeyId:     aws.String("FRESHKEY"),
+		SecretAccessKey: aws.String("fresh-secret"),
+		SessionToken:    aws.String("fresh-token"),
+		Expiration:      awsTime(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)),
+	}
+
+	if err := s.SaveAssumedRole("prod-readonly", creds); err != nil {
+		t.Fatalf("SaveAssumedRole failed: %v", err)
+	}
+
+	raw, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		t.Fatalf("failed to read back credentials file: %v", err)
+	}
+	if !strings.Contains(string(raw), "a hand-written comment") {
+		t.Fatalf("expected the existing comment to survive a save, got:\n%s", raw)
+	}
+
+	cfg, err := ini.Load(credentialsFile)
+	if err != nil {
+		t.Fatalf("failed to parse saved credentials file: %v", err)
+	}
+
+	defaultSec, err := cfg.GetSection("default")
+	if err != nil {
+		t.Fatalf("expected the unrelated [default] section to survive a save: %v", err)
+	}
+	if defaultSec.Key("aws_access_key_id").String() != "DEFAULTKEY" {
+		t.Fatalf("expected [default] to be untouched, got %q", defaultSec.Key("aws_access_key_id").String())
+	}
+
+	sec, err := cfg.GetSection("prod-readonly")
+	if err != nil {
+		t.Fatalf("expected [prod-readonly] to still exist: %v", err)
+	}
+	if sec.Key("aws_access_key_id").String() != "FRESHKEY" {
+		t.Fatalf("expected the stale key to be overwritten, got %q", sec.Key("aws_access_key_id").String())
+	}
+	if sec.Key("aws_secret_access_key").String() != "fresh-secret" {
+		t.Fatalf("expected the stale secret to be overwritten, got %q", sec.Key("aws_secret_access_key").String())
+	}
+	if sec.Key("expiration").String() != "2030-01-02T03:04:05Z" {
+		t.Fatalf("unexpected expiration value %q", sec.Key("expiration").String())
+	}
+}
+
+func awsTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,76 @@
+package credstore
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/99designs/keyring"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/pkg/errors"
+
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+)
+
+// keyringServiceName namespaces osdctl's entries in the shared OS keyring.
+const keyringServiceName = "osdctl"
+
+// keyringEntry is the JSON payload stored under a profile's keyring item.
+type keyringEntry struct {
+	AccessKeyID     string    `json:"aws_access_key_id"`
+	SecretAccessKey string    `json:"aws_secret_access_key"`
+	SessionToken    string    `json:"aws_session_token"`
+	Expiration      time.Time `json:"expiration"`
+}
+
+func openKeyring() (keyring.Keyring, error) {
+	return keyring.Open(keyring.Config{ServiceName: keyringServiceName})
+}
+
+func (s *Store) saveToKeyring(profile string, creds *ststypes.Credentials) error {
+	kr, err := openKeyring()
+	if err != nil {
+		return errors.Wrap(err, "failed to open OS keyring")
+	}
+
+	entry := keyringEntry{
+		AccessKeyID:     awsString(creds.AccessKeyId),
+		SecretAccessKey: awsString(creds.SecretAccessKey),
+		SessionToken:    awsString(creds.SessionToken),
+	}
+	if creds.Expiration != nil {
+		entry.Expiration = *creds.Expiration
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal credentials")
+	}
+
+	return kr.Set(keyring.Item{
+		Key:  profile,
+		Data: data,
+	})
+}
+
+func (s *Store) loadFromKeyring(profile string) (*awsprovider.AwsClientInput, error) {
+	kr, err := openKeyring()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open OS keyring")
+	}
+
+	item, err := kr.Get(profile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "no profile %s in OS keyring", profile)
+	}
+
+	var entry keyringEntry
+	if err := json.Unmarshal(item.Data, &entry); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal credentials")
+	}
+
+	return &awsprovider.AwsClientInput{
+		AccessKeyID:     entry.AccessKeyID,
+		SecretAccessKey: entry.SecretAccessKey,
+		SessionToken:    entry.SessionToken,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package objectstore
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+)
+
+// AzureBlobStore adapts an Azure Blob service client to the ObjectStore
+// interface for ARO cluster teardown, treating containers as buckets.
+type AzureBlobStore struct {
+	client *azblob.Client
+}
+
+// NewAzureBlobStore returns an ObjectStore backed by client.
+func NewAzureBlobStore(client *azblob.Client) *AzureBlobStore {
+	return &AzureBlobStore{client: client}
+}
+
+func (a *AzureBlobStore) ListBuckets(ctx context.Context) ([]string, error) {
+	var names []string
+	pager := a.client.NewListContainersPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range page.ContainerItems {
+			names = append(names, *c.Name)
+		}
+	}
+	return names, nil
+}
+
+func (a *AzureBlobStore) ListObjectsPaginated(ctx context.Context, bucket string, pageFunc func(keys []string) error) error {
+	pager := a.client.NewListBlobsFlatPager(bucket, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(page.Segment.BlobItems))
+		for _, b := range page.Segment.BlobItems {
+			keys = append(keys, *b.Name)
+		}
+		if len(keys) > 0 {
+			if err := pageFunc(keys); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (a *AzureBlobStore) DeleteBucket(ctx context.Context, bucket string) error {
+	_, err := a.client.DeleteContainer(ctx, bucket, nil)
+	return err
+}
+
+// BulkDeleteObjects deletes keys one at a time: Azure Blob has no batch-delete API.
+func (a *AzureBlobStore) BulkDeleteObjects(ctx context.Context, bucket string, keys []string) error {
+	for _, key := range keys {
+		if _, err := a.client.DeleteBlob(ctx, bucket, key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package objectstore
+
+import (
+	"context"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// GCSStore adapts a GCS client to the ObjectStore interface for OSD-GCP
+// cluster teardown.
+type GCSStore struct {
+	client    *storage.Client
+	projectID string
+}
+
+// NewGCSStore returns an ObjectStore backed by client, scoped to projectID.
+func NewGCSStore(client *storage.Client, projectID string) *GCSStore {
+	return &GCSStore{client: client, projectID: projectID}
+}
+
+func (g *GCSStore) ListBuckets(ctx context.Context) ([]string, error) {
+	var names []string
+	it := g.client.Buckets(ctx, g.projectID)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, attrs.Name)
+	}
+}
+
+func (g *GCSStore) ListObjectsPaginated(ctx context.Context, bucket string, pageFunc func(keys []string) error) error {
+	var keys []string
+	it := g.client.Bucket(bucket).Objects(ctx, nil)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, attrs.Name)
+		if len(keys) == deleteBatchSize {
+			if err := pageFunc(keys); err != nil {
+				return err
+			}
+			keys = nil
+		}
+	}
+
+	if len(keys) > 0 {
+		return pageFunc(keys)
+	}
+	return nil
+}
+
+func (g *GCSStore) DeleteBucket(ctx context.Context, bucket string) error {
+	return g.client.Bucket(bucket).Delete(ctx)
+}
+
+// BulkDeleteObjects deletes keys one at a time: GCS has no batch-delete API.
+func (g *GCSStore) BulkDeleteObjects(ctx context.Context, bucket string, keys []string) error {
+	b := g.client.Bucket(bucket)
+	for _, key := range keys {
+		if err := b.Object(key).Delete(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Package objectstore provides a provider-agnostic object storage surface so
+// cluster teardown code can clean up buckets on ROSA (S3), OSD-GCP (GCS), and
+// ARO (Azure Blob) through a single interface.
+package objectstore
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// deleteBatchSize is the largest number of keys BulkDeleteObjects will send to a
+// single provider call, matching the S3 DeleteObjects limit that every backend
+// is sized against for consistency.
+const deleteBatchSize = 1000
+
+// ObjectStore is a provider-agnostic object storage surface. Implementations
+// exist for S3 (and S3-compatible backends), GCS, and Azure Blob.
+type ObjectStore interface {
+	// ListBuckets returns the names of every bucket/container visible to the
+	// configured credentials.
+	ListBuckets(ctx context.Context) ([]string, error)
+
+	// ListObjectsPaginated walks every object key in bucket, invoking pageFunc
+	// once per page of keys. Returning an error from pageFunc stops iteration.
+	ListObjectsPaginated(ctx context.Context, bucket string, pageFunc func(keys []string) error) error
+
+	// DeleteBucket removes an empty bucket.
+	DeleteBucket(ctx context.Context, bucket string) error
+
+	// BulkDeleteObjects deletes keys from bucket, batching/paginating as the
+	// backend requires, and returns an error if any key could not be deleted.
+	// It stops at the first provider call that fails, so keys after that call
+	// are not attempted.
+	BulkDeleteObjects(ctx context.Context, bucket string, keys []string) error
+}
+
+// VersionPurger is implemented by ObjectStore backends (currently S3Store)
+// whose provider keeps old object versions/delete markers around after the
+// current objects are deleted. DeleteBucketsWithPrefix calls it once per
+// bucket, after emptying it, so versioned buckets are actually left empty.
+type VersionPurger interface {
+	PurgeObjectVersions(ctx context.Context, bucket string) error
+}
+
+// BucketSummary reports the outcome of DeleteBucketsWithPrefix for a single
+// matching bucket: its name, and how many objects it holds (dry run) or how
+// many were deleted (real run).
+type BucketSummary struct {
+	Name        string
+	ObjectCount int
+}
+
+// DeleteBucketsWithPrefix is the default, cross-provider implementation of
+// cluster teardown: it empties and removes every bucket in store whose name
+// starts with prefix. When dryRun is true, matching buckets are reported along
+// with their object counts without deleting anything.
+func DeleteBucketsWithPrefix(ctx context.Context, store ObjectStore, prefix string, dryRun bool) ([]BucketSummary, error) {
+	buckets, err := store.ListBuckets(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list buckets")
+	}
+
+	var results []BucketSummary
+	for _, bucket := range buckets {
+		if !strings.HasPrefix(bucket, prefix) {
+			continue
+		}
+
+		if dryRun {
+			count, err := countObjects(ctx, store, bucket)
+			if err != nil {
+				return results, errors.Wrapf(err, "failed to count objects in bucket %s", bucket)
+			}
+			results = append(results, BucketSummary{Name: bucket, ObjectCount: count})
+			continue
+		}
+
+		count, err := emptyBucket(ctx, store, bucket)
+		if err != nil {
+			return results, errors.Wrapf(err, "failed to empty bucket %s", bucket)
+		}
+
+		if purger, ok := store.(VersionPurger); ok {
+			if err := purger.PurgeObjectVersions(ctx, bucket); err != nil {
+				return results, errors.Wrapf(err, "failed to purge object versions in bucket %s", bucket)
+			}
+		}
+
+		if err := store.DeleteBucket(ctx, bucket); err != nil {
+			return results, errors.Wrapf(err, "failed to delete bucket %s", bucket)
+		}
+
+		results = append(results, BucketSummary{Name: bucket, ObjectCount: count})
+	}
+
+	return results, nil
+}
+
+// countObjects returns the total number of objects in bucket without deleting
+// anything, for dry-run reporting.
+func countObjects(ctx context.Context, store ObjectStore, bucket string) (int, error) {
+	count := 0
+	err := store.ListObjectsPaginated(ctx, bucket, func(keys []string) error {
+		count += len(keys)
+		return nil
+	})
+	return count, err
+}
+
+// emptyBucket deletes every object in bucket, batching deletes to respect
+// provider-specific limits, and returns how many objects were deleted.
+func emptyBucket(ctx context.Context, store ObjectStore, bucket string) (int, error) {
+	count := 0
+	err := store.ListObjectsPaginated(ctx, bucket, func(keys []string) error {
+		for start := 0; start < len(keys); start += deleteBatchSize {
+			end := start + deleteBatchSize
+			if end > len(keys) {
+				end = len(keys)
+			}
+			if err := store.BulkDeleteObjects(ctx, bucket, keys[start:end]); err != nil {
+				return err
+			}
+			count += end - start
+		}
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,118 @@
+package objectstore
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeStore is an in-memory ObjectStore used to test DeleteBucketsWithPrefix
+// without a live S3/GCS/Azure backend.
+type fakeStore struct {
+	buckets map[string][]string
+	deleted map[string]bool
+}
+
+func newFakeStore(buckets map[string][]string) *fakeStore {
+	return &fakeStore{buckets: buckets, deleted: map[string]bool{}}
+}
+
+func (f *fakeStore) ListBuckets(ctx context.Context) ([]string, error) {
+	var names []string
+	for name := range f.buckets {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f *fakeStore) ListObjectsPaginated(ctx context.Context, bucket string, pageFunc func(keys []string) error) error {
+	keys := f.buckets[bucket]
+	for start := 0; start < len(keys); start += 2 {
+		end := start + 2
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := pageFunc(keys[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeStore) DeleteBucket(ctx context.Context, bucket string) error {
+	f.deleted[bucket] = true
+	return nil
+}
+
+func (f *fakeStore) BulkDeleteObjects(ctx context.Context, bucket string, keys []string) error {
+	remaining := f.buckets[bucket][:0]
+	toDelete := map[string]bool{}
+	for _, k := range keys {
+		toDelete[k] = true
+	}
+	for _, k := range f.buckets[bucket] {
+		if !toDelete[k] {
+			remaining = append(remaining, k)
+		}
+	}
+	f.buckets[bucket] = remaining
+	return nil
+}
+
+func TestDeleteBucketsWithPrefixDryRunReportsObjectCounts(t *testing.T) {
+	store := newFakeStore(map[string][]string{
+		"cluster-abc-logs": {"a", "b", "c"},
+		"cluster-abc-data": {"x"},
+		"unrelated-bucket": {"y", "z"},
+	})
+
+	results, err := DeleteBucketsWithPrefix(context.Background(), store, "cluster-abc", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Name] = r.ObjectCount
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 matching buckets, got %d", len(results))
+	}
+	if counts["cluster-abc-logs"] != 3 {
+		t.Fatalf("expected 3 objects reported for cluster-abc-logs, got %d", counts["cluster-abc-logs"])
+	}
+	if counts["cluster-abc-data"] != 1 {
+		t.Fatalf("expected 1 object reported for cluster-abc-data, got %d", counts["cluster-abc-data"])
+	}
+	if store.deleted["cluster-abc-logs"] || store.deleted["cluster-abc-data"] {
+		t.Fatalf("dry run must not delete anything")
+	}
+	if len(store.buckets["cluster-abc-logs"]) != 3 {
+		t.Fatalf("dry run must not remove objects")
+	}
+}
+
+func TestDeleteBucketsWithPrefixDeletesMatchingBuckets(t *testing.T) {
+	store := newFakeStore(map[string][]string{
+		"cluster-abc-logs": {"a", "b", "c"},
+		"unrelated-bucket": {"y"},
+	})
+
+	results, err := DeleteBucketsWithPrefix(context.Background(), store, "cluster-abc", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Name != "cluster-abc-logs" {
+		t.Fatalf("expected only cluster-abc-logs to be deleted, got %+v", results)
+	}
+	if results[0].ObjectCount != 3 {
+		t.Fatalf("expected 3 objects deleted, got %d", results[0].ObjectCount)
+	}
+	if !store.deleted["cluster-abc-logs"] {
+		t.Fatalf("expected cluster-abc-logs to be deleted")
+	}
+	if store.deleted["unrelated-bucket"] {
+		t.Fatalf("unrelated-bucket must not be deleted")
+	}
+}
@@ -0,0 +1,93 @@
+package objectstore
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/pkg/errors"
+
+	awsprovider "github.com/openshift/osdctl/pkg/provider/aws"
+)
+
+// S3Store adapts an awsprovider.Client to the ObjectStore interface.
+type S3Store struct {
+	client awsprovider.Client
+}
+
+// NewS3Store returns an ObjectStore backed by the given AWS client.
+func NewS3Store(client awsprovider.Client) *S3Store {
+	return &S3Store{client: client}
+}
+
+func (s *S3Store) ListBuckets(ctx context.Context) ([]string, error) {
+	out, err := s.client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(out.Buckets))
+	for _, b := range out.Buckets {
+		names = append(names, aws.ToString(b.Name))
+	}
+	return names, nil
+}
+
+// ListObjectsPaginated delegates to the client's ListObjectsV2 pager so large
+// buckets are walked a page at a time instead of being truncated at 1000 keys.
+func (s *S3Store) ListObjectsPaginated(ctx context.Context, bucket string, pageFunc func(keys []string) error) error {
+	var pageErr error
+	err := s.client.ListObjectsPagesWithContext(ctx, &s3.ListObjectsV2Input{Bucket: aws.String(bucket)}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		keys := make([]string, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+		if len(keys) > 0 {
+			if pageErr = pageFunc(keys); pageErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return pageErr
+}
+
+func (s *S3Store) DeleteBucket(ctx context.Context, bucket string) error {
+	_, err := s.client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: aws.String(bucket)})
+	return err
+}
+
+// BulkDeleteObjects delegates to the client's chunked, retrying bulk delete and
+// surfaces any per-object failures as an error.
+func (s *S3Store) BulkDeleteObjects(ctx context.Context, bucket string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	_, failed, err := s.client.BulkDeleteObjects(ctx, bucket, keys)
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to delete %d of %d objects from bucket %s", len(failed), len(keys), bucket)
+	}
+	return nil
+}
+
+// PurgeObjectVersions implements objectstore.VersionPurger so
+// DeleteBucketsWithPrefix removes old versions and delete markers left behind
+// in a versioned bucket, in a single pass after the current objects are gone.
+func (s *S3Store) PurgeObjectVersions(ctx context.Context, bucket string) error {
+	_, failed, err := s.client.PurgeObjectVersions(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to purge %d object version(s) from bucket %s", len(failed), bucket)
+	}
+	return nil
+}